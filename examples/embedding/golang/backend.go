@@ -0,0 +1,72 @@
+package xdl
+
+import "fmt"
+
+// Backend selects how a Context resolves CallFunction.
+type Backend int
+
+const (
+	// BackendNative dispatches to the native XDL library first, falling
+	// back to the Registry (the behavior of NewContext).
+	BackendNative Backend = iota
+	// BackendGo dispatches every call straight to the Registry, skipping
+	// the native library entirely.
+	BackendGo
+	// BackendSoftFloat dispatches to a pure-Go, bit-reproducible software
+	// floating-point implementation (see softfloat.go), for pipelines that
+	// need identical results across architectures rather than raw speed.
+	BackendSoftFloat
+)
+
+// Option configures a Context created by NewContextWithOptions.
+type Option func(*contextOptions)
+
+type contextOptions struct {
+	backend Backend
+}
+
+// WithBackend selects the Backend a Context dispatches CallFunction through.
+func WithBackend(b Backend) Option {
+	return func(o *contextOptions) { o.backend = b }
+}
+
+// NewContextWithOptions creates a Context with the given Options applied.
+// With no options (or WithBackend(BackendNative)) it behaves like
+// NewContext. BackendGo and BackendSoftFloat never touch the native
+// library, so they succeed even when libxdl_ffi isn't installed.
+func NewContextWithOptions(opts ...Option) (*Context, error) {
+	var o contextOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	switch o.backend {
+	case BackendGo, BackendSoftFloat:
+		return &Context{ptr: dummyHandle(), monitor: newHealthMonitor(), backend: o.backend}, nil
+	default:
+		return NewContext()
+	}
+}
+
+// dispatchGo resolves functionName against the Registry, used directly by
+// BackendGo and as the fallback for BackendNative.
+func dispatchGo(functionName string, args []float64) (float64, error) {
+	fn, ok := defaultRegistry.Lookup(functionName)
+	if !ok {
+		return 0, fmt.Errorf("xdl: unknown function %q", functionName)
+	}
+	return fn(args...)
+}
+
+// dispatchSoftFloat resolves functionName against the software floating
+// point backend.
+func dispatchSoftFloat(functionName string, args []float64) (float64, error) {
+	fn, ok := softFloatFns[functionName]
+	if !ok {
+		return 0, fmt.Errorf("xdl: softfloat backend has no implementation for %q", functionName)
+	}
+	if len(args) != 1 {
+		return 0, fmt.Errorf("xdl: %s wants 1 argument, got %d", functionName, len(args))
+	}
+	return fn(args[0]), nil
+}
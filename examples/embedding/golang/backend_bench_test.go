@@ -0,0 +1,30 @@
+package xdl
+
+import "testing"
+
+// BenchmarkBackendSin compares the BackendGo and BackendSoftFloat
+// CallFunction backends on the same workload, so the softfloat backend's
+// reproducibility-for-speed tradeoff against BackendGo (plain math.Sin) is
+// visible in `go test -bench`. BackendNative is not benchmarked here.
+func BenchmarkBackendSin(b *testing.B) {
+	backends := []struct {
+		name    string
+		backend Backend
+	}{
+		{"Go", BackendGo},
+		{"SoftFloat", BackendSoftFloat},
+	}
+
+	for _, bk := range backends {
+		ctx, err := NewContextWithOptions(WithBackend(bk.backend))
+		if err != nil {
+			b.Fatalf("NewContextWithOptions(%s): %v", bk.name, err)
+		}
+		b.Run(bk.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				ctx.CallFunction("sin", 0.6)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+//go:build !noxdl
+
+package xdl
+
+/*
+#include <stdlib.h>
+
+extern double xdl_call_function_batch(void* context, const char* functionName, double* dst, double* srcs, int nargs, int n);
+*/
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+// CallFunctionSlice calls an XDL function over entire buffers in a single cgo
+// transition. dst and every slice in args must have the same length; args are
+// passed to the native function positionally, one value per argument per
+// element. This amortizes the per-call cgo and C.CString overhead that makes
+// CallFunction unsuitable for tight numeric loops.
+func (c *Context) CallFunctionSlice(functionName string, dst []float64, args ...[]float64) error {
+	ptr := c.loadPtr()
+	if ptr == nil {
+		panic("XDL context is closed")
+	}
+
+	// BackendGo/BackendSoftFloat contexts carry a placeholder ptr that
+	// xdl_init never produced; handing it to the native batch entry point
+	// would be as wrong as CallFunction calling the native library for
+	// them. Fall back to the same per-element path those backends use for
+	// CallFunction.
+	if c.backend != BackendNative {
+		return c.callFunctionSliceGo(functionName, dst, args...)
+	}
+
+	n := len(dst)
+	for i, a := range args {
+		if len(a) != n {
+			return fmt.Errorf("xdl: argument %d has length %d, want %d", i, len(a), n)
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+
+	funcNameC := C.CString(functionName)
+	defer C.free(unsafe.Pointer(funcNameC))
+
+	// Pack args into one contiguous, Go-owned buffer (arg i occupies
+	// srcs[i*n:(i+1)*n]) rather than handing cgo a slice of pointers: a
+	// []*C.double would be a Go pointer whose elements are themselves Go
+	// pointers into separate backing arrays, which cgo's pointer checks
+	// reject at runtime.
+	srcs := make([]float64, len(args)*n)
+	for i, a := range args {
+		copy(srcs[i*n:(i+1)*n], a)
+	}
+	var srcsPtr *C.double
+	if len(srcs) > 0 {
+		srcsPtr = (*C.double)(unsafe.Pointer(&srcs[0]))
+	}
+
+	C.xdl_call_function_batch(ptr, funcNameC, (*C.double)(unsafe.Pointer(&dst[0])), srcsPtr, C.int(len(args)), C.int(n))
+	return nil
+}
+
+// SinSlice computes sin(src[i]) for every element into dst.
+func (c *Context) SinSlice(dst, src []float64) error { return c.CallFunctionSlice("sin", dst, src) }
+
+// CosSlice computes cos(src[i]) for every element into dst.
+func (c *Context) CosSlice(dst, src []float64) error { return c.CallFunctionSlice("cos", dst, src) }
+
+// ExpSlice computes exp(src[i]) for every element into dst.
+func (c *Context) ExpSlice(dst, src []float64) error { return c.CallFunctionSlice("exp", dst, src) }
+
+// LogSlice computes log(src[i]) for every element into dst.
+func (c *Context) LogSlice(dst, src []float64) error { return c.CallFunctionSlice("log", dst, src) }
@@ -0,0 +1,74 @@
+//go:build !noxdl
+
+package xdl
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// benchSizes runs from a single element up to the 100,000 elements of the
+// demo's performance test, so regressions against math.Sin show up at the
+// same scale users will see.
+var benchSizes = []int{1, 100, 1_000, 10_000, 100_000}
+
+func randSlice(n int) []float64 {
+	r := rand.New(rand.NewSource(42))
+	s := make([]float64, n)
+	for i := range s {
+		s[i] = r.Float64() * math.Pi * 2
+	}
+	return s
+}
+
+// BenchmarkSinScalar drives the native function one element at a time, the
+// way the demo's performance test does, as the baseline the batch API is
+// meant to beat.
+func BenchmarkSinScalar(b *testing.B) {
+	for _, n := range benchSizes {
+		src := randSlice(n)
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			ctx := defaultContext
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				for _, x := range src {
+					ctx.CallFunction("sin", x)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkSinSlice drives the batch entry point, one cgo call per b.N
+// iteration regardless of slice size.
+func BenchmarkSinSlice(b *testing.B) {
+	for _, n := range benchSizes {
+		src := randSlice(n)
+		dst := make([]float64, n)
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			ctx := defaultContext
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				ctx.SinSlice(dst, src)
+			}
+		})
+	}
+}
+
+// BenchmarkSinMath is the math.Sin reference the demo compares XDL against.
+func BenchmarkSinMath(b *testing.B) {
+	for _, n := range benchSizes {
+		src := randSlice(n)
+		dst := make([]float64, n)
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				for j, x := range src {
+					dst[j] = math.Sin(x)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+//go:build noxdl
+
+package xdl
+
+// CallFunctionSlice is the noxdl counterpart of the native batch entry
+// point: it has no cgo call to amortize, so it just applies CallFunction
+// (which is itself Registry-backed under this build tag) element by
+// element. Kept so callers like the interp package can depend on one batch
+// API regardless of build tag.
+func (c *Context) CallFunctionSlice(functionName string, dst []float64, args ...[]float64) error {
+	return c.callFunctionSliceGo(functionName, dst, args...)
+}
+
+// SinSlice computes sin(src[i]) for every element into dst.
+func (c *Context) SinSlice(dst, src []float64) error { return c.CallFunctionSlice("sin", dst, src) }
+
+// CosSlice computes cos(src[i]) for every element into dst.
+func (c *Context) CosSlice(dst, src []float64) error { return c.CallFunctionSlice("cos", dst, src) }
+
+// ExpSlice computes exp(src[i]) for every element into dst.
+func (c *Context) ExpSlice(dst, src []float64) error { return c.CallFunctionSlice("exp", dst, src) }
+
+// LogSlice computes log(src[i]) for every element into dst.
+func (c *Context) LogSlice(dst, src []float64) error { return c.CallFunctionSlice("log", dst, src) }
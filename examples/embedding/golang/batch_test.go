@@ -0,0 +1,42 @@
+//go:build !noxdl
+
+package xdl
+
+import (
+	"math"
+	"testing"
+)
+
+// TestCallFunctionSliceGoBackend exercises the BackendGo fallback path
+// CallFunctionSlice takes instead of handing the native batch entry point a
+// placeholder context pointer.
+func TestCallFunctionSliceGoBackend(t *testing.T) {
+	ctx, err := NewContextWithOptions(WithBackend(BackendGo))
+	if err != nil {
+		t.Fatalf("NewContextWithOptions: %v", err)
+	}
+
+	src := []float64{0, 0.5, 1, 1.5}
+	dst := make([]float64, len(src))
+	if err := ctx.SinSlice(dst, src); err != nil {
+		t.Fatalf("SinSlice: %v", err)
+	}
+	for i, x := range src {
+		if want := math.Sin(x); math.Abs(dst[i]-want) > 1e-12 {
+			t.Errorf("dst[%d] = %v, want %v", i, dst[i], want)
+		}
+	}
+}
+
+func TestCallFunctionSliceLengthMismatch(t *testing.T) {
+	ctx, err := NewContextWithOptions(WithBackend(BackendGo))
+	if err != nil {
+		t.Fatalf("NewContextWithOptions: %v", err)
+	}
+
+	dst := make([]float64, 3)
+	src := make([]float64, 2)
+	if err := ctx.CallFunctionSlice("sin", dst, src); err == nil {
+		t.Fatalf("CallFunctionSlice with mismatched lengths returned no error")
+	}
+}
@@ -0,0 +1,96 @@
+//go:build !noxdl
+
+package xdl
+
+/*
+#cgo LDFLAGS: -L. -lxdl_ffi -lm
+#cgo darwin LDFLAGS: -L. -lxdl_ffi
+#cgo linux LDFLAGS: -L. -lxdl_ffi -lm
+#cgo windows LDFLAGS: -L. -lxdl_ffi
+
+#include <stdlib.h>
+#include <math.h>
+
+// XDL C API declarations. xdl_call_function_status reports whether
+// functionName was recognized via its return code rather than overloading
+// the result itself, so a legitimate domain-error NaN (e.g. asin(2)) can't
+// be confused with "no such function".
+extern void* xdl_init();
+extern void xdl_cleanup(void* context);
+extern int xdl_call_function_status(void* context, const char* functionName, double* args, int nargs, double* result);
+*/
+import "C"
+import (
+	"errors"
+	"unsafe"
+)
+
+// nativeHandle owns the cgo-side XDL context pointer.
+type nativeHandle = unsafe.Pointer
+
+// goBackendHandle is a non-nil placeholder used by BackendGo and
+// BackendSoftFloat contexts, which never call xdl_init.
+var goBackendHandle = unsafe.Pointer(&struct{}{})
+
+func dummyHandle() nativeHandle { return goBackendHandle }
+
+// NewContext creates a new XDL context
+func NewContext() (*Context, error) {
+	ptr := C.xdl_init()
+	if ptr == nil {
+		return nil, errors.New("failed to initialize XDL context")
+	}
+	return &Context{ptr: ptr, monitor: newHealthMonitor()}, nil
+}
+
+// reinit discards the current native handle and acquires a fresh one,
+// called by the heartbeat supervisor when the phi-accrual detector trips.
+// BackendGo and BackendSoftFloat contexts hold no native handle, so for
+// them it does nothing.
+func (c *Context) reinit() error {
+	if c.backend != BackendNative {
+		return nil
+	}
+	ptr := C.xdl_init()
+	if ptr == nil {
+		return errors.New("failed to reinitialize XDL context")
+	}
+	old := c.swapPtr(ptr)
+	if old != nil {
+		C.xdl_cleanup(old)
+	}
+	return nil
+}
+
+// Close cleans up the XDL context
+func (c *Context) Close() {
+	old := c.swapPtr(nil)
+	if old != nil {
+		C.xdl_cleanup(old)
+	}
+}
+
+// nativeCall invokes the native XDL entry point against ptr, returning
+// ok=false when the native library's status code says it doesn't recognize
+// functionName, so CallFunction can fall back to the Registry. A
+// domain-error NaN from a function the native side does recognize
+// (asin(2), log(-1), ...) comes back with ok=true and is returned as-is,
+// not rerouted to the fallback. ptr is passed in by the caller (rather than
+// read from c.ptr here) so CallFunction can hold it across the call even if
+// Close or reinit swaps c.ptr out concurrently.
+func (c *Context) nativeCall(ptr nativeHandle, functionName string, args []float64) (float64, bool) {
+	funcNameC := C.CString(functionName)
+	defer C.free(unsafe.Pointer(funcNameC))
+
+	var argsPtr *C.double
+	if len(args) > 0 {
+		argsPtr = (*C.double)(unsafe.Pointer(&args[0]))
+	}
+
+	var result C.double
+	status := C.xdl_call_function_status(ptr, funcNameC, argsPtr, C.int(len(args)), &result)
+	if status == 0 {
+		return 0, false
+	}
+	return float64(result), true
+}
@@ -0,0 +1,127 @@
+//go:build !noxdl
+
+package xdl
+
+/*
+#include <stdlib.h>
+
+// XdlValue carries one typed argument or result across the cgo boundary.
+// kind: 0=scalar, 1=complex, 2=vector, 3=matrix. data is length-prefixed
+// (len, or rows*cols for matrices) rather than a raw double*, and is NULL
+// whenever that length is 0 -- callers must never dereference data without
+// checking len/rows*cols first.
+typedef struct {
+	int kind;
+	double re, im;
+	int len;
+	double* data;
+	int rows, cols;
+} XdlValue;
+
+extern int xdl_call_function_v(void* context, const char* functionName, XdlValue* args, int nargs, XdlValue* result);
+*/
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+// CallFunctionV calls an XDL function with typed arguments, covering
+// signatures CallFunction can't express: complex scalars, vectors, and
+// matrices. It tries the native library and returns an error if the native
+// side doesn't recognize functionName; unlike CallFunction it has no
+// Registry fallback, since the Registry only holds scalar IntrinsicFuncs.
+func (c *Context) CallFunctionV(functionName string, args ...Value) (Value, error) {
+	ptr := c.loadPtr()
+	if ptr == nil {
+		panic("XDL context is closed")
+	}
+
+	funcNameC := C.CString(functionName)
+	defer C.free(unsafe.Pointer(funcNameC))
+
+	// cArgs must be a C-allocated array, not a Go slice of C.XdlValue: each
+	// element's data field is a Go pointer into the corresponding Value's
+	// backing slice, and a Go pointer whose pointee itself holds further Go
+	// pointers is exactly what cgo's cgocheck rejects at runtime. Allocating
+	// the array itself in C memory sidesteps that -- the per-element data
+	// fields still point at Go memory, but the array we hand to C is not a
+	// Go pointer, so only the (args-slice-rooted) Go objects it references
+	// need to stay reachable, which they already are for the call's
+	// duration since args is still in scope.
+	var argsPtr *C.XdlValue
+	if len(args) > 0 {
+		cArgsMem := C.malloc(C.size_t(len(args)) * C.size_t(unsafe.Sizeof(C.XdlValue{})))
+		defer C.free(cArgsMem)
+		cArgs := unsafe.Slice((*C.XdlValue)(cArgsMem), len(args))
+
+		for i, a := range args {
+			v, err := toCValue(a)
+			if err != nil {
+				return Value{}, fmt.Errorf("xdl: argument %d: %w", i, err)
+			}
+			cArgs[i] = v
+		}
+		argsPtr = &cArgs[0]
+	}
+
+	var result C.XdlValue
+	if C.xdl_call_function_v(ptr, funcNameC, argsPtr, C.int(len(args)), &result) == 0 {
+		return Value{}, fmt.Errorf("xdl: %s: native call failed", functionName)
+	}
+	return fromCValue(result), nil
+}
+
+// toCValue converts a Value to its C representation. Vector/matrix data
+// fields point directly at the Value's own backing slice. An empty slice
+// leaves data as NULL instead of taking the address of a zero-length
+// slice's (nonexistent) first element.
+func toCValue(v Value) (cv C.XdlValue, err error) {
+	switch v.Kind {
+	case KindScalar:
+		return C.XdlValue{kind: 0, re: C.double(v.Scalar)}, nil
+	case KindComplex:
+		return C.XdlValue{kind: 1, re: C.double(real(v.Complex)), im: C.double(imag(v.Complex))}, nil
+	case KindVector:
+		cv = C.XdlValue{kind: 2, len: C.int(len(v.Vector))}
+		if len(v.Vector) > 0 {
+			cv.data = (*C.double)(unsafe.Pointer(&v.Vector[0]))
+		}
+		return cv, nil
+	case KindMatrix:
+		if len(v.Matrix.Data) != v.Matrix.Rows*v.Matrix.Cols {
+			return cv, fmt.Errorf("matrix data length %d doesn't match %dx%d", len(v.Matrix.Data), v.Matrix.Rows, v.Matrix.Cols)
+		}
+		cv = C.XdlValue{kind: 3, rows: C.int(v.Matrix.Rows), cols: C.int(v.Matrix.Cols)}
+		if len(v.Matrix.Data) > 0 {
+			cv.data = (*C.double)(unsafe.Pointer(&v.Matrix.Data[0]))
+		}
+		return cv, nil
+	default:
+		return cv, fmt.Errorf("unknown value kind %d", v.Kind)
+	}
+}
+
+// fromCValue copies a native XdlValue result back into Go-owned memory.
+func fromCValue(cv C.XdlValue) Value {
+	switch cv.kind {
+	case 1:
+		return ComplexValue(complex(float64(cv.re), float64(cv.im)))
+	case 2:
+		n := int(cv.len)
+		vec := make([]float64, n)
+		if n > 0 {
+			copy(vec, unsafe.Slice((*float64)(unsafe.Pointer(cv.data)), n))
+		}
+		return VectorValue(vec)
+	case 3:
+		n := int(cv.rows) * int(cv.cols)
+		data := make([]float64, n)
+		if n > 0 {
+			copy(data, unsafe.Slice((*float64)(unsafe.Pointer(cv.data)), n))
+		}
+		return MatrixValue(Matrix{Rows: int(cv.rows), Cols: int(cv.cols), Data: data})
+	default:
+		return ScalarValue(float64(cv.re))
+	}
+}
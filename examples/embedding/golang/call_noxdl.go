@@ -0,0 +1,37 @@
+//go:build noxdl
+
+package xdl
+
+// nativeHandle is a non-nil sentinel standing in for the cgo context pointer
+// when built without the native library. It carries no native state; every
+// call is served by the Registry.
+type nativeHandle = *struct{}
+
+var noxdlHandle = &struct{}{}
+
+func dummyHandle() nativeHandle { return noxdlHandle }
+
+// NewContext creates a context that dispatches every call through the
+// Registry; the noxdl build tag never links libxdl_ffi.
+func NewContext() (*Context, error) {
+	return &Context{ptr: noxdlHandle, monitor: newHealthMonitor()}, nil
+}
+
+// Close releases the context. There is no native resource to free under
+// noxdl.
+func (c *Context) Close() {
+	c.swapPtr(nil)
+}
+
+// reinit only restores the sentinel handle under noxdl (reopening a closed
+// context): there is no native handle to replace.
+func (c *Context) reinit() error {
+	c.swapPtr(noxdlHandle)
+	return nil
+}
+
+// nativeCall always misses under noxdl so CallFunction falls back to the
+// Registry for every function name.
+func (c *Context) nativeCall(ptr nativeHandle, functionName string, args []float64) (float64, bool) {
+	return 0, false
+}
@@ -0,0 +1,28 @@
+//go:build noxdl
+
+package xdl
+
+import "fmt"
+
+// CallFunctionV has no native library to dispatch to under noxdl. It only
+// serves requests that have a pure-Go implementation (currently "matmul",
+// also reachable directly via Context.MatMul) and errors otherwise.
+func (c *Context) CallFunctionV(functionName string, args ...Value) (Value, error) {
+	if c.loadPtr() == nil {
+		panic("XDL context is closed")
+	}
+
+	switch functionName {
+	case "matmul":
+		if len(args) != 2 || args[0].Kind != KindMatrix || args[1].Kind != KindMatrix {
+			return Value{}, fmt.Errorf("xdl: matmul wants 2 matrix arguments")
+		}
+		m, err := c.MatMul(args[0].Matrix, args[1].Matrix)
+		if err != nil {
+			return Value{}, err
+		}
+		return MatrixValue(m), nil
+	default:
+		return Value{}, fmt.Errorf("xdl: %s: no noxdl implementation", functionName)
+	}
+}
@@ -0,0 +1,156 @@
+package xdl
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// defaultHeartbeatWindow is the number of recent heartbeat inter-arrival
+// times kept for the phi-accrual calculation.
+const defaultHeartbeatWindow = 20
+
+// healthMonitor implements a phi-accrual failure detector (Hayashibara et
+// al.) over the heartbeat intervals of a Context's native handle: it keeps
+// a sliding window of the last N inter-arrival times, fits a normal
+// distribution to them, and reports how surprising the current silence is
+// in log-probability units (phi).
+type healthMonitor struct {
+	mu        sync.Mutex
+	intervals []float64
+	lastBeat  time.Time
+	stop      chan struct{}
+}
+
+func newHealthMonitor() *healthMonitor {
+	return &healthMonitor{}
+}
+
+// probe reinitializes c if the detector has tripped over the silence since
+// the last heartbeat, then issues one heartbeat. It recovers from a panicking CallFunction (e.g. Close racing with this
+// goroutine, or the call itself failing) so a single bad heartbeat can't
+// take down the process that StartHeartbeat is meant to protect; a missed
+// heartbeat just widens the silence phi is measuring, which is itself a
+// signal the detector already knows how to act on.
+func (h *healthMonitor) probe(c *Context, threshold float64) {
+	defer func() { recover() }()
+
+	if h.phi(time.Now()) >= threshold {
+		c.reinit()
+	}
+	c.CallFunction("noop")
+	h.recordBeat(time.Now())
+}
+
+// recordBeat folds a heartbeat arriving at now into the sliding window.
+func (h *healthMonitor) recordBeat(now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.lastBeat.IsZero() {
+		h.intervals = append(h.intervals, now.Sub(h.lastBeat).Seconds())
+		if len(h.intervals) > defaultHeartbeatWindow {
+			h.intervals = h.intervals[len(h.intervals)-defaultHeartbeatWindow:]
+		}
+	}
+	h.lastBeat = now
+}
+
+// phi returns the phi-accrual suspicion level at time now: -log10 of the
+// probability, under a normal model of the recorded intervals, that the
+// next heartbeat still hasn't arrived. It is 0 until enough heartbeats have
+// been recorded to fit a distribution.
+func (h *healthMonitor) phi(now time.Time) float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.intervals) < 2 || h.lastBeat.IsZero() {
+		return 0
+	}
+
+	mean, stddev := meanStddev(h.intervals)
+	if stddev == 0 {
+		stddev = 1e-9
+	}
+
+	elapsed := now.Sub(h.lastBeat).Seconds()
+	p := 1 - normalCDF((elapsed-mean)/stddev)
+	if p <= 0 {
+		return math.Inf(1)
+	}
+	return -math.Log10(p)
+}
+
+func meanStddev(xs []float64) (mean, stddev float64) {
+	for _, x := range xs {
+		mean += x
+	}
+	mean /= float64(len(xs))
+
+	for _, x := range xs {
+		d := x - mean
+		stddev += d * d
+	}
+	stddev = math.Sqrt(stddev / float64(len(xs)))
+	return mean, stddev
+}
+
+// normalCDF is the standard normal cumulative distribution function.
+func normalCDF(x float64) float64 {
+	return 0.5 * (1 + math.Erf(x/math.Sqrt2))
+}
+
+// Phi returns the context's current phi-accrual suspicion level: roughly,
+// -log10 of the probability that the native handle is still alive given
+// how long it's been since the last successful heartbeat. It rises sharply
+// once the elapsed silence exceeds the historical heartbeat interval by a
+// few standard deviations.
+func (c *Context) Phi() float64 {
+	return c.monitor.phi(time.Now())
+}
+
+// Healthy reports whether Phi is below threshold. A typical threshold is
+// 8-16; see Hayashibara et al., "The phi Accrual Failure Detector".
+func (c *Context) Healthy(threshold float64) bool {
+	return c.Phi() < threshold
+}
+
+// StartHeartbeat launches a goroutine that probes the native context on
+// interval with a cheap no-op call and feeds the resulting inter-arrival
+// times into the phi-accrual detector. Whenever Phi crosses threshold, the
+// context is transparently reinitialized so the next CallFunction succeeds
+// against a fresh native handle instead of a wedged one. It returns a stop
+// function that ends the goroutine; calling StartHeartbeat again before
+// stopping the previous one is a no-op.
+func (c *Context) StartHeartbeat(interval time.Duration, threshold float64) (stop func()) {
+	h := c.monitor
+
+	h.mu.Lock()
+	if h.stop != nil {
+		h.mu.Unlock()
+		return func() {}
+	}
+	stopCh := make(chan struct{})
+	h.stop = stopCh
+	h.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopCh:
+				return
+			case <-ticker.C:
+				h.probe(c, threshold)
+			}
+		}
+	}()
+
+	return func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if h.stop != nil {
+			close(h.stop)
+			h.stop = nil
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package xdl
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestHealthMonitorPhiZeroBeforeEnoughBeats(t *testing.T) {
+	h := newHealthMonitor()
+	now := time.Unix(0, 0)
+
+	if p := h.phi(now); p != 0 {
+		t.Fatalf("phi with no beats = %v, want 0", p)
+	}
+
+	h.recordBeat(now)
+	if p := h.phi(now.Add(time.Second)); p != 0 {
+		t.Fatalf("phi with a single beat = %v, want 0", p)
+	}
+}
+
+func TestHealthMonitorPhiRisesWithSilence(t *testing.T) {
+	h := newHealthMonitor()
+	now := time.Unix(0, 0)
+
+	for i := 0; i < 10; i++ {
+		h.recordBeat(now)
+		now = now.Add(time.Second)
+	}
+
+	phiOnTime := h.phi(now)
+	phiLate := h.phi(now.Add(30 * time.Second))
+
+	if !(phiLate > phiOnTime) {
+		t.Fatalf("phi after a long silence (%v) did not exceed phi right on schedule (%v)", phiLate, phiOnTime)
+	}
+}
+
+func TestHealthMonitorPhiInfiniteOnExtremeSilence(t *testing.T) {
+	h := newHealthMonitor()
+	now := time.Unix(0, 0)
+	for i := 0; i < 10; i++ {
+		h.recordBeat(now)
+		now = now.Add(time.Second)
+	}
+
+	p := h.phi(now.Add(365 * 24 * time.Hour))
+	if !math.IsInf(p, 1) {
+		t.Fatalf("phi after a year of silence = %v, want +Inf", p)
+	}
+}
+
+func TestMeanStddev(t *testing.T) {
+	mean, stddev := meanStddev([]float64{1, 2, 3, 4, 5})
+	if mean != 3 {
+		t.Fatalf("mean = %v, want 3", mean)
+	}
+	wantStddev := math.Sqrt(2)
+	if math.Abs(stddev-wantStddev) > 1e-9 {
+		t.Fatalf("stddev = %v, want %v", stddev, wantStddev)
+	}
+}
+
+func TestNormalCDF(t *testing.T) {
+	if math.Abs(normalCDF(0)-0.5) > 1e-9 {
+		t.Fatalf("normalCDF(0) = %v, want 0.5", normalCDF(0))
+	}
+	if normalCDF(-10) >= normalCDF(0) || normalCDF(0) >= normalCDF(10) {
+		t.Fatalf("normalCDF is not monotonically increasing around 0")
+	}
+}
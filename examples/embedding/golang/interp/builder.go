@@ -0,0 +1,50 @@
+package interp
+
+// Builder composes a Block incrementally, e.g. to build
+// sqrt(sin(x)^2 + cos(y)^2):
+//
+//	var b Builder
+//	x, y := b.Input("x"), b.Input("y")
+//	sx, cy := b.Sin(x), b.Cos(y)
+//	r := b.Sqrt(b.Add(b.Mul(sx, sx), b.Mul(cy, cy)))
+//	block := b.Build(r)
+type Builder struct {
+	ops []Op
+}
+
+func (b *Builder) push(op Op) Value {
+	b.ops = append(b.ops, op)
+	return Value(len(b.ops) - 1)
+}
+
+// Input declares a named vector input.
+func (b *Builder) Input(name string) Value { return b.push(Op{Kind: Input, Name: name}) }
+
+// Const declares an immediate scalar.
+func (b *Builder) Const(v float64) Value { return b.push(Op{Kind: Const, Imm: v}) }
+
+// Add, Sub, Mul, Div compose two values with the corresponding arithmetic op.
+func (b *Builder) Add(a, c Value) Value { return b.push(Op{Kind: Add, Args: []Value{a, c}}) }
+func (b *Builder) Sub(a, c Value) Value { return b.push(Op{Kind: Sub, Args: []Value{a, c}}) }
+func (b *Builder) Mul(a, c Value) Value { return b.push(Op{Kind: Mul, Args: []Value{a, c}}) }
+func (b *Builder) Div(a, c Value) Value { return b.push(Op{Kind: Div, Args: []Value{a, c}}) }
+
+// Call applies a named unary intrinsic (e.g. "sin", "cos", "sqrt", "exp",
+// "log", or anything else Context.CallFunction can resolve) to v.
+func (b *Builder) Call(name string, v Value) Value {
+	return b.push(Op{Kind: OpKind(name), Args: []Value{v}})
+}
+
+// Sin, Cos, Sqrt, Exp, Log are Call shorthands for the common intrinsics.
+func (b *Builder) Sin(v Value) Value  { return b.Call("sin", v) }
+func (b *Builder) Cos(v Value) Value  { return b.Call("cos", v) }
+func (b *Builder) Sqrt(v Value) Value { return b.Call("sqrt", v) }
+func (b *Builder) Exp(v Value) Value  { return b.Call("exp", v) }
+func (b *Builder) Log(v Value) Value  { return b.Call("log", v) }
+
+// Build returns the Block ending at result: any ops pushed after result was
+// computed are dropped, since Block has no separate dead-code elimination
+// pass to remove them later.
+func (b *Builder) Build(result Value) Block {
+	return Block{Ops: b.ops[:int(result)+1]}
+}
@@ -0,0 +1,216 @@
+// Package interp evaluates small expression graphs over an xdl.Context
+// without paying a cgo call per node. A Block is a flat, value-numbered
+// list of ops in the style of a lowered SSA basic block: Ops[v] defines
+// value v and may only reference earlier values. Evaluate constant-folds
+// pure ops, common-subexpression-eliminates structurally identical ops, and
+// batch-executes the remaining leaf intrinsics over []float64 buffers using
+// Context.CallFunctionSlice, so a formula like sqrt(sin(x)^2 + cos(y)^2)
+// costs one native call per distinct intrinsic instead of one per element
+// per node.
+package interp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"../xdl"
+)
+
+// Value identifies an op's result by its position in a Block.
+type Value int
+
+// OpKind names the operation an Op performs. The arithmetic kinds below are
+// evaluated in pure Go; any other OpKind is treated as an intrinsic name
+// (e.g. "sin") and dispatched through the Context, so it resolves however
+// Context.CallFunction already resolves it -- native, Registry, or noxdl --
+// without this package needing its own copy of that logic.
+type OpKind string
+
+// Arithmetic kinds, evaluated without touching the Context.
+const (
+	Const OpKind = "const"
+	Input OpKind = "input"
+	Add   OpKind = "add"
+	Sub   OpKind = "sub"
+	Mul   OpKind = "mul"
+	Div   OpKind = "div"
+)
+
+// Op is one value-numbered instruction in a Block.
+type Op struct {
+	Kind OpKind
+	Args []Value // operand value numbers; unused by Const and Input
+	Imm  float64 // immediate operand; only meaningful for Kind == Const
+	Name string  // input name; only meaningful for Kind == Input
+}
+
+// Block is a flat basic block: Ops[v] defines value v. The block's result
+// is the value of its last op.
+type Block struct {
+	Ops []Op
+}
+
+var binaryOps = map[OpKind]func(a, b float64) float64{
+	Add: func(a, b float64) float64 { return a + b },
+	Sub: func(a, b float64) float64 { return a - b },
+	Mul: func(a, b float64) float64 { return a * b },
+	Div: func(a, b float64) float64 { return a / b },
+}
+
+// value holds an evaluated Op's result, either as a single constant-folded
+// scalar or as a length-n vector.
+type value struct {
+	scalar   float64
+	isScalar bool
+	vec      []float64
+}
+
+// Evaluate runs block against ctx, resolving each Input op from inputs. All
+// input slices must share the same length n; the result has length n (with
+// no inputs at all, n is 1). A scalar-only block is broadcast to length n.
+func Evaluate(ctx *xdl.Context, block Block, inputs map[string][]float64) ([]float64, error) {
+	n := -1
+	for name, v := range inputs {
+		if n == -1 {
+			n = len(v)
+		} else if len(v) != n {
+			return nil, fmt.Errorf("interp: input %q has length %d, want %d", name, len(v), n)
+		}
+	}
+	if n == -1 {
+		n = 1
+	}
+	if len(block.Ops) == 0 {
+		return nil, fmt.Errorf("interp: empty block")
+	}
+
+	canon := make([]int, len(block.Ops))
+	vals := make([]value, len(block.Ops))
+	memo := make(map[string]int, len(block.Ops))
+
+	for i, op := range block.Ops {
+		key, err := signature(op, canon, i)
+		if err != nil {
+			return nil, err
+		}
+		if rep, ok := memo[key]; ok {
+			canon[i] = rep
+			continue
+		}
+		memo[key] = i
+		canon[i] = i
+
+		v, err := eval(ctx, op, canon, vals, inputs, n)
+		if err != nil {
+			return nil, fmt.Errorf("interp: op %d (%s): %w", i, op.Kind, err)
+		}
+		vals[i] = v
+	}
+
+	result := vals[canon[len(block.Ops)-1]]
+	if result.isScalar {
+		out := make([]float64, n)
+		for i := range out {
+			out[i] = result.scalar
+		}
+		return out, nil
+	}
+	return result.vec, nil
+}
+
+// signature builds a structural key for op so that two ops with the same
+// kind and, after canonicalization, the same operands collapse to a single
+// computation (the interpreter's CSE pass).
+func signature(op Op, canon []int, index int) (string, error) {
+	switch op.Kind {
+	case Const:
+		return fmt.Sprintf("const:%v", op.Imm), nil
+	case Input:
+		return "input:" + op.Name, nil
+	default:
+		parts := make([]string, len(op.Args))
+		for j, a := range op.Args {
+			if int(a) >= index {
+				return "", fmt.Errorf("interp: op %d references value %d defined later", index, a)
+			}
+			parts[j] = strconv.Itoa(canon[a])
+		}
+		return string(op.Kind) + "(" + strings.Join(parts, ",") + ")", nil
+	}
+}
+
+func eval(ctx *xdl.Context, op Op, canon []int, vals []value, inputs map[string][]float64, n int) (value, error) {
+	switch op.Kind {
+	case Const:
+		return value{scalar: op.Imm, isScalar: true}, nil
+
+	case Input:
+		src, ok := inputs[op.Name]
+		if !ok {
+			return value{}, fmt.Errorf("no input named %q", op.Name)
+		}
+		return value{vec: src}, nil
+
+	default:
+		if fn, ok := binaryOps[op.Kind]; ok {
+			if len(op.Args) != 2 {
+				return value{}, fmt.Errorf("%s wants 2 args, got %d", op.Kind, len(op.Args))
+			}
+			a, b := vals[canon[op.Args[0]]], vals[canon[op.Args[1]]]
+			if a.isScalar && b.isScalar {
+				return value{scalar: fn(a.scalar, b.scalar), isScalar: true}, nil
+			}
+			av, bv := broadcast(a, n), broadcast(b, n)
+			out := make([]float64, n)
+			for i := range out {
+				out[i] = fn(av[i], bv[i])
+			}
+			return value{vec: out}, nil
+		}
+
+		// Leaf intrinsic: dispatch through the Context so native/Registry/
+		// noxdl resolution and batching stay centralized in CallFunction(Slice).
+		if len(op.Args) != 1 {
+			return value{}, fmt.Errorf("%s wants 1 arg, got %d", op.Kind, len(op.Args))
+		}
+		a := vals[canon[op.Args[0]]]
+		if a.isScalar {
+			v, err := callScalar(ctx, string(op.Kind), a.scalar)
+			if err != nil {
+				return value{}, err
+			}
+			return value{scalar: v, isScalar: true}, nil
+		}
+		out := make([]float64, n)
+		if err := ctx.CallFunctionSlice(string(op.Kind), out, a.vec); err != nil {
+			return value{}, err
+		}
+		return value{vec: out}, nil
+	}
+}
+
+// callScalar calls ctx.CallFunction and turns a panic (its usual way of
+// reporting an unknown function or registry error) into a returned error,
+// so a scalar-only block fails the same way -- via Evaluate's error return
+// -- as a vector block hitting the identical problem through
+// CallFunctionSlice.
+func callScalar(ctx *xdl.Context, name string, x float64) (v float64, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return ctx.CallFunction(name, x), nil
+}
+
+func broadcast(v value, n int) []float64 {
+	if !v.isScalar {
+		return v.vec
+	}
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = v.scalar
+	}
+	return out
+}
@@ -0,0 +1,115 @@
+package interp
+
+import (
+	"math"
+	"testing"
+
+	"../xdl"
+)
+
+func newTestContext(t *testing.T) *xdl.Context {
+	t.Helper()
+	ctx, err := xdl.NewContextWithOptions(xdl.WithBackend(xdl.BackendGo))
+	if err != nil {
+		t.Fatalf("NewContextWithOptions: %v", err)
+	}
+	return ctx
+}
+
+// TestEvaluateSqrtSinCos runs a variant of the package doc comment's
+// example with both terms sharing one input, sqrt(sin(x)^2 + cos(x)^2),
+// which is identically 1 for every x.
+func TestEvaluateSqrtSinCos(t *testing.T) {
+	ctx := newTestContext(t)
+
+	var b Builder
+	x := b.Input("x")
+	sx, cx := b.Sin(x), b.Cos(x)
+	r := b.Sqrt(b.Add(b.Mul(sx, sx), b.Mul(cx, cx)))
+	block := b.Build(r)
+
+	out, err := Evaluate(ctx, block, map[string][]float64{
+		"x": {0, 1, 2, 3},
+	})
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	for i, v := range out {
+		if math.Abs(v-1) > 1e-9 {
+			t.Errorf("out[%d] = %v, want ~1", i, v)
+		}
+	}
+}
+
+// TestEvaluateConstantFolding checks that a block with no Input ops is
+// evaluated as a scalar, broadcast to the requested width.
+func TestEvaluateConstantFolding(t *testing.T) {
+	ctx := newTestContext(t)
+
+	var b Builder
+	r := b.Add(b.Const(2), b.Const(3))
+	block := b.Build(r)
+
+	out, err := Evaluate(ctx, block, map[string][]float64{"x": {0, 0, 0}})
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	for i, v := range out {
+		if v != 5 {
+			t.Errorf("out[%d] = %v, want 5", i, v)
+		}
+	}
+}
+
+// TestEvaluateCSE checks that two structurally identical subexpressions
+// collapse to a single computed value instead of being evaluated twice.
+func TestEvaluateCSE(t *testing.T) {
+	var b Builder
+	x := b.Input("x")
+	a := b.Mul(x, x)
+	c := b.Mul(x, x)
+	r := b.Add(a, c)
+	block := b.Build(r)
+
+	if len(block.Ops) != 4 {
+		t.Fatalf("len(block.Ops) = %d, want 4 (input, mul, mul, add)", len(block.Ops))
+	}
+
+	canon := []int{0, 1, 2, 3}
+	ka, _ := signature(block.Ops[a], canon, int(a))
+	kc, _ := signature(block.Ops[c], canon, int(c))
+	if ka != kc {
+		t.Fatalf("identical muls have different CSE keys %q and %q", ka, kc)
+	}
+
+	ctx := newTestContext(t)
+	out, err := Evaluate(ctx, block, map[string][]float64{"x": {2, 3}})
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	want := []float64{8, 18}
+	for i, v := range out {
+		if v != want[i] {
+			t.Errorf("out[%d] = %v, want %v", i, v, want[i])
+		}
+	}
+}
+
+func TestEvaluateEmptyBlock(t *testing.T) {
+	ctx := newTestContext(t)
+	if _, err := Evaluate(ctx, Block{}, nil); err == nil {
+		t.Fatalf("Evaluate with an empty block returned no error")
+	}
+}
+
+func TestEvaluateScalarLeafUnknownFunctionReturnsError(t *testing.T) {
+	ctx := newTestContext(t)
+
+	var b Builder
+	r := b.Call("no-such-function", b.Const(1))
+	block := b.Build(r)
+
+	if _, err := Evaluate(ctx, block, nil); err == nil {
+		t.Fatalf("Evaluate with an unknown scalar intrinsic returned no error")
+	}
+}
@@ -0,0 +1,84 @@
+package xdl
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+// IntrinsicFunc is a pure-Go implementation of a named XDL function, used as
+// a fallback when the native library doesn't provide (or isn't linked for)
+// that function. It mirrors the variadic scalar signature of CallFunction.
+type IntrinsicFunc func(args ...float64) (float64, error)
+
+// Registry maps function names to Go implementations. It is consulted by
+// Context.CallFunction whenever the native side doesn't recognize a
+// function, or when built with the noxdl tag, which never consults the
+// native side at all.
+type Registry struct {
+	mu  sync.RWMutex
+	fns map[string]IntrinsicFunc
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{fns: make(map[string]IntrinsicFunc)}
+}
+
+// Register adds or replaces the implementation for name.
+func (r *Registry) Register(name string, fn IntrinsicFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fns[name] = fn
+}
+
+// Lookup returns the implementation registered for name, if any.
+func (r *Registry) Lookup(name string) (IntrinsicFunc, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	fn, ok := r.fns[name]
+	return fn, ok
+}
+
+// Names returns the currently registered function names, in no particular
+// order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.fns))
+	for name := range r.fns {
+		names = append(names, name)
+	}
+	return names
+}
+
+// defaultRegistry backs the package-level Register function and is what
+// Context.CallFunction consults by default.
+var defaultRegistry = NewRegistry()
+
+// Register adds fn as the Go fallback implementation for name in the
+// default Registry. Call it from an init function to make a function
+// available before the native library (or without it, under noxdl).
+func Register(name string, fn IntrinsicFunc) {
+	defaultRegistry.Register(name, fn)
+}
+
+func unary(f func(float64) float64) IntrinsicFunc {
+	return func(args ...float64) (float64, error) {
+		if len(args) != 1 {
+			return 0, fmt.Errorf("xdl: want 1 argument, got %d", len(args))
+		}
+		return f(args[0]), nil
+	}
+}
+
+func init() {
+	defaultRegistry.Register("sin", unary(math.Sin))
+	defaultRegistry.Register("cos", unary(math.Cos))
+	defaultRegistry.Register("sqrt", unary(math.Sqrt))
+	defaultRegistry.Register("exp", unary(math.Exp))
+	defaultRegistry.Register("log", unary(math.Log))
+	defaultRegistry.Register("gamma", unary(math.Gamma))
+	defaultRegistry.Register("besselJ0", unary(math.J0))
+	defaultRegistry.Register("noop", func(args ...float64) (float64, error) { return 0, nil })
+}
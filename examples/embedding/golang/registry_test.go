@@ -0,0 +1,68 @@
+package xdl
+
+import "testing"
+
+func TestRegistryRegisterLookup(t *testing.T) {
+	r := NewRegistry()
+
+	if _, ok := r.Lookup("double"); ok {
+		t.Fatalf("Lookup(%q) found an implementation before Register", "double")
+	}
+
+	r.Register("double", func(args ...float64) (float64, error) {
+		return args[0] * 2, nil
+	})
+
+	fn, ok := r.Lookup("double")
+	if !ok {
+		t.Fatalf("Lookup(%q) = false after Register", "double")
+	}
+	v, err := fn(21)
+	if err != nil {
+		t.Fatalf("fn(21) returned error: %v", err)
+	}
+	if v != 42 {
+		t.Fatalf("fn(21) = %v, want 42", v)
+	}
+}
+
+func TestRegistryRegisterReplaces(t *testing.T) {
+	r := NewRegistry()
+	r.Register("one", func(args ...float64) (float64, error) { return 1, nil })
+	r.Register("one", func(args ...float64) (float64, error) { return 2, nil })
+
+	fn, ok := r.Lookup("one")
+	if !ok {
+		t.Fatalf("Lookup(%q) = false", "one")
+	}
+	v, _ := fn()
+	if v != 2 {
+		t.Fatalf("second Register didn't replace the first: fn() = %v, want 2", v)
+	}
+}
+
+func TestRegistryNames(t *testing.T) {
+	r := NewRegistry()
+	r.Register("a", func(args ...float64) (float64, error) { return 0, nil })
+	r.Register("b", func(args ...float64) (float64, error) { return 0, nil })
+
+	names := r.Names()
+	if len(names) != 2 {
+		t.Fatalf("Names() = %v, want 2 entries", names)
+	}
+	seen := map[string]bool{}
+	for _, n := range names {
+		seen[n] = true
+	}
+	if !seen["a"] || !seen["b"] {
+		t.Fatalf("Names() = %v, want both %q and %q", names, "a", "b")
+	}
+}
+
+func TestDefaultRegistryIntrinsics(t *testing.T) {
+	for _, name := range []string{"sin", "cos", "sqrt", "exp", "log", "gamma", "besselJ0", "noop"} {
+		if _, ok := defaultRegistry.Lookup(name); !ok {
+			t.Errorf("defaultRegistry has no implementation for %q", name)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package xdl
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSoftFloatAgainstMath(t *testing.T) {
+	const tol = 1e-9
+
+	inputs := []float64{0, 0.1, 0.5, 1, 1.5, 2, 3.14159, -0.5, -2, 10}
+
+	for _, x := range inputs {
+		if got, want := sfSin(x), math.Sin(x); math.Abs(got-want) > tol {
+			t.Errorf("sfSin(%v) = %v, want %v (math.Sin)", x, got, want)
+		}
+		if got, want := sfCos(x), math.Cos(x); math.Abs(got-want) > tol {
+			t.Errorf("sfCos(%v) = %v, want %v (math.Cos)", x, got, want)
+		}
+		if got, want := sfExp(x), math.Exp(x); math.Abs(got-want) > tol*math.Max(1, math.Abs(want)) {
+			t.Errorf("sfExp(%v) = %v, want %v (math.Exp)", x, got, want)
+		}
+	}
+
+	for _, x := range []float64{0.01, 0.5, 1, 2, 10, 1000} {
+		if got, want := sfSqrt(x), math.Sqrt(x); math.Abs(got-want) > tol*math.Max(1, want) {
+			t.Errorf("sfSqrt(%v) = %v, want %v (math.Sqrt)", x, got, want)
+		}
+		if got, want := sfLog(x), math.Log(x); math.Abs(got-want) > tol*math.Max(1, math.Abs(want)) {
+			t.Errorf("sfLog(%v) = %v, want %v (math.Log)", x, got, want)
+		}
+	}
+}
+
+func TestSoftFloatSpecialCases(t *testing.T) {
+	if !math.IsNaN(sfSqrt(-1)) {
+		t.Errorf("sfSqrt(-1) = %v, want NaN", sfSqrt(-1))
+	}
+	if sfSqrt(0) != 0 {
+		t.Errorf("sfSqrt(0) = %v, want 0", sfSqrt(0))
+	}
+	if !math.IsNaN(sfLog(-1)) {
+		t.Errorf("sfLog(-1) = %v, want NaN", sfLog(-1))
+	}
+	if !math.IsInf(sfLog(0), -1) {
+		t.Errorf("sfLog(0) = %v, want -Inf", sfLog(0))
+	}
+	if !math.IsNaN(sfSin(math.NaN())) {
+		t.Errorf("sfSin(NaN) = %v, want NaN", sfSin(math.NaN()))
+	}
+}
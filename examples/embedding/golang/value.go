@@ -0,0 +1,80 @@
+package xdl
+
+import "fmt"
+
+// ValueKind tags which field of a Value is meaningful.
+type ValueKind int
+
+const (
+	KindScalar ValueKind = iota
+	KindComplex
+	KindVector
+	KindMatrix
+)
+
+// Matrix is a row-major, dense Rows x Cols matrix; len(Data) must equal
+// Rows*Cols.
+type Matrix struct {
+	Rows, Cols int
+	Data       []float64
+}
+
+// Value is a tagged union of the argument/result types CallFunctionV can
+// carry across the native boundary: a plain scalar, a complex scalar, a
+// vector, or a matrix. Only the field named by Kind is meaningful.
+type Value struct {
+	Kind    ValueKind
+	Scalar  float64
+	Complex complex128
+	Vector  []float64
+	Matrix  Matrix
+}
+
+// ScalarValue, ComplexValue, VectorValue, and MatrixValue build a Value of
+// the corresponding kind.
+func ScalarValue(x float64) Value     { return Value{Kind: KindScalar, Scalar: x} }
+func ComplexValue(z complex128) Value { return Value{Kind: KindComplex, Complex: z} }
+func VectorValue(v []float64) Value   { return Value{Kind: KindVector, Vector: v} }
+func MatrixValue(m Matrix) Value      { return Value{Kind: KindMatrix, Matrix: m} }
+
+func (v Value) String() string {
+	switch v.Kind {
+	case KindScalar:
+		return fmt.Sprintf("%g", v.Scalar)
+	case KindComplex:
+		return fmt.Sprintf("%g", v.Complex)
+	case KindVector:
+		return fmt.Sprintf("vector(len=%d)", len(v.Vector))
+	case KindMatrix:
+		return fmt.Sprintf("matrix(%dx%d)", v.Matrix.Rows, v.Matrix.Cols)
+	default:
+		return "invalid"
+	}
+}
+
+// MatMul multiplies two matrices. It is implemented directly in Go rather
+// than dispatched through CallFunctionV: matrix multiplication is cheap to
+// get right in pure Go and doesn't benefit from a cgo round trip the way an
+// FFT or a linear solver does.
+func (c *Context) MatMul(a, b Matrix) (Matrix, error) {
+	if a.Cols != b.Rows {
+		return Matrix{}, fmt.Errorf("xdl: MatMul: a is %dx%d, b is %dx%d", a.Rows, a.Cols, b.Rows, b.Cols)
+	}
+	if len(a.Data) != a.Rows*a.Cols || len(b.Data) != b.Rows*b.Cols {
+		return Matrix{}, fmt.Errorf("xdl: MatMul: matrix data length doesn't match Rows*Cols")
+	}
+
+	out := Matrix{Rows: a.Rows, Cols: b.Cols, Data: make([]float64, a.Rows*b.Cols)}
+	for i := 0; i < a.Rows; i++ {
+		for k := 0; k < a.Cols; k++ {
+			aik := a.Data[i*a.Cols+k]
+			if aik == 0 {
+				continue
+			}
+			for j := 0; j < b.Cols; j++ {
+				out.Data[i*out.Cols+j] += aik * b.Data[k*b.Cols+j]
+			}
+		}
+	}
+	return out, nil
+}
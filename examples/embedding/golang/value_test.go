@@ -0,0 +1,64 @@
+package xdl
+
+import "testing"
+
+func TestValueConstructorsAndString(t *testing.T) {
+	cases := []struct {
+		v    Value
+		kind ValueKind
+		want string
+	}{
+		{ScalarValue(3.5), KindScalar, "3.5"},
+		{ComplexValue(complex(1, 2)), KindComplex, "(1+2i)"},
+		{VectorValue([]float64{1, 2, 3}), KindVector, "vector(len=3)"},
+		{MatrixValue(Matrix{Rows: 2, Cols: 3, Data: make([]float64, 6)}), KindMatrix, "matrix(2x3)"},
+	}
+
+	for _, c := range cases {
+		if c.v.Kind != c.kind {
+			t.Errorf("Kind = %v, want %v", c.v.Kind, c.kind)
+		}
+		if got := c.v.String(); got != c.want {
+			t.Errorf("String() = %q, want %q", got, c.want)
+		}
+	}
+}
+
+func TestContextMatMul(t *testing.T) {
+	ctx, err := NewContextWithOptions(WithBackend(BackendGo))
+	if err != nil {
+		t.Fatalf("NewContextWithOptions: %v", err)
+	}
+
+	a := Matrix{Rows: 2, Cols: 3, Data: []float64{1, 2, 3, 4, 5, 6}}
+	b := Matrix{Rows: 3, Cols: 2, Data: []float64{7, 8, 9, 10, 11, 12}}
+
+	got, err := ctx.MatMul(a, b)
+	if err != nil {
+		t.Fatalf("MatMul: %v", err)
+	}
+
+	want := Matrix{Rows: 2, Cols: 2, Data: []float64{58, 64, 139, 154}}
+	if got.Rows != want.Rows || got.Cols != want.Cols {
+		t.Fatalf("MatMul shape = %dx%d, want %dx%d", got.Rows, got.Cols, want.Rows, want.Cols)
+	}
+	for i := range want.Data {
+		if got.Data[i] != want.Data[i] {
+			t.Errorf("MatMul.Data[%d] = %v, want %v", i, got.Data[i], want.Data[i])
+		}
+	}
+}
+
+func TestContextMatMulDimensionMismatch(t *testing.T) {
+	ctx, err := NewContextWithOptions(WithBackend(BackendGo))
+	if err != nil {
+		t.Fatalf("NewContextWithOptions: %v", err)
+	}
+
+	a := Matrix{Rows: 2, Cols: 3, Data: make([]float64, 6)}
+	b := Matrix{Rows: 2, Cols: 2, Data: make([]float64, 4)}
+
+	if _, err := ctx.MatMul(a, b); err == nil {
+		t.Fatalf("MatMul with mismatched inner dimensions returned no error")
+	}
+}
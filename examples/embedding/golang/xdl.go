@@ -1,63 +1,95 @@
 // Package xdl provides Go bindings for the XDL scientific computing library
 package xdl
 
-/*
-#cgo LDFLAGS: -L. -lxdl_ffi -lm
-#cgo darwin LDFLAGS: -L. -lxdl_ffi
-#cgo linux LDFLAGS: -L. -lxdl_ffi -lm
-#cgo windows LDFLAGS: -L. -lxdl_ffi
-
-#include <stdlib.h>
-
-// XDL C API declarations
-extern void* xdl_init();
-extern void xdl_cleanup(void* context);
-extern double xdl_call_function(void* context, const char* functionName, double* args, int nargs);
-*/
-import "C"
 import (
-	"errors"
-	"unsafe"
+	"fmt"
+	"sync"
 )
 
 // Context represents an XDL execution context
 type Context struct {
-	ptr unsafe.Pointer
+	mu      sync.RWMutex
+	ptr     nativeHandle
+	monitor *healthMonitor
+	backend Backend
 }
 
-// NewContext creates a new XDL context
-func NewContext() (*Context, error) {
-	ptr := C.xdl_init()
-	if ptr == nil {
-		return nil, errors.New("failed to initialize XDL context")
-	}
-	return &Context{ptr: ptr}, nil
+// loadPtr returns the current native handle. Reading it through the mutex
+// (rather than field access) keeps it safe to call concurrently with
+// Close/reinit, which StartHeartbeat's background goroutine can trigger
+// while CallFunction is running on another goroutine.
+func (c *Context) loadPtr() nativeHandle {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ptr
 }
 
-// Close cleans up the XDL context
-func (c *Context) Close() {
-	if c.ptr != nil {
-		C.xdl_cleanup(c.ptr)
-		c.ptr = nil
-	}
+// swapPtr replaces the native handle and returns the previous one, used by
+// Close (swap to nil) and reinit (swap to a freshly initialized handle).
+func (c *Context) swapPtr(p nativeHandle) nativeHandle {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	old := c.ptr
+	c.ptr = p
+	return old
 }
 
-// CallFunction calls an XDL function with scalar arguments
+// CallFunction calls an XDL function with scalar arguments. BackendNative
+// (the default, used by NewContext) dispatches to the native library
+// first; if the native side doesn't know the function (or this binary was
+// built with the noxdl tag, which never links the native library at all)
+// it falls back to a Go implementation registered in the default Registry.
+// BackendGo and BackendSoftFloat, selected via NewContextWithOptions, skip
+// the native library and go straight to the Registry or the software
+// floating-point implementation respectively.
 func (c *Context) CallFunction(functionName string, args ...float64) float64 {
-	if c.ptr == nil {
+	ptr := c.loadPtr()
+	if ptr == nil {
 		panic("XDL context is closed")
 	}
 
-	funcNameC := C.CString(functionName)
-	defer C.free(unsafe.Pointer(funcNameC))
+	var (
+		v   float64
+		err error
+	)
+	switch c.backend {
+	case BackendGo:
+		v, err = dispatchGo(functionName, args)
+	case BackendSoftFloat:
+		v, err = dispatchSoftFloat(functionName, args)
+	default:
+		if nv, ok := c.nativeCall(ptr, functionName, args); ok {
+			return nv
+		}
+		v, err = dispatchGo(functionName, args)
+	}
+	if err != nil {
+		panic(err.Error())
+	}
+	return v
+}
 
-	var argsPtr *C.double
-	if len(args) > 0 {
-		argsPtr = (*C.double)(unsafe.Pointer(&args[0]))
+// callFunctionSliceGo evaluates functionName element-wise through
+// CallFunction. It's the shared fallback for any batch call that can't go
+// through the native batch entry point: BackendGo/BackendSoftFloat
+// contexts (whose ptr is a placeholder, not something xdl_init produced)
+// and the entire noxdl build.
+func (c *Context) callFunctionSliceGo(functionName string, dst []float64, args ...[]float64) error {
+	n := len(dst)
+	for i, a := range args {
+		if len(a) != n {
+			return fmt.Errorf("xdl: argument %d has length %d, want %d", i, len(a), n)
+		}
 	}
 
-	result := C.xdl_call_function(c.ptr, funcNameC, argsPtr, C.int(len(args)))
-	return float64(result)
+	row := make([]float64, len(args))
+	for i := 0; i < n; i++ {
+		for j, a := range args {
+			row[j] = a[i]
+		}
+		dst[i] = c.CallFunction(functionName, row...)
+	}
+	return nil
 }
 
 // Convenience methods for common mathematical functions